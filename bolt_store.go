@@ -0,0 +1,111 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+package scheduler
+
+import (
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltJobsBucket is the single BoltDB bucket BoltStore keeps jobs in.
+var boltJobsBucket = []byte("scheduler_jobs")
+
+// BoltStore persists jobs to a BoltDB file, so they survive a process
+// restart.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (creating if necessary) a BoltDB database at path and
+// returns a Store backed by it.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltJobsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// Close releases the underlying BoltDB file handle.
+func (b *BoltStore) Close() error {
+	return b.db.Close()
+}
+
+// SaveJob implements Store.
+func (b *BoltStore) SaveJob(job *PersistedJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltJobsBucket).Put([]byte(job.ID), data)
+	})
+}
+
+// LoadJobs implements Store.
+func (b *BoltStore) LoadJobs() ([]*PersistedJob, error) {
+	var jobs []*PersistedJob
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltJobsBucket).ForEach(func(k, v []byte) error {
+			job := new(PersistedJob)
+			if err := json.Unmarshal(v, job); err != nil {
+				return err
+			}
+			jobs = append(jobs, job)
+			return nil
+		})
+	})
+
+	return jobs, err
+}
+
+// MarkCompleted implements Store.
+func (b *BoltStore) MarkCompleted(id string) error {
+	return b.DeleteJob(id)
+}
+
+// DeleteJob implements Store.
+func (b *BoltStore) DeleteJob(id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltJobsBucket).Delete([]byte(id))
+	})
+}
+
+// UpdateLastRun implements Store.
+func (b *BoltStore) UpdateLastRun(id string, t time.Time) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltJobsBucket)
+
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+
+		job := new(PersistedJob)
+		if err := json.Unmarshal(data, job); err != nil {
+			return err
+		}
+		job.LastRun = t
+
+		data, err := json.Marshal(job)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(id), data)
+	})
+}
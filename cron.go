@@ -0,0 +1,280 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrCronFields is returned when a cron expression does not have 5 or 6
+	// whitespace separated fields.
+	ErrCronFields = errors.New("cron expression must have 5 or 6 fields")
+
+	// ErrCronNoMatch is returned when a CronSched can not match any time in
+	// the foreseeable future, e.g. "0 0 30 2 *" (February 30th never happens).
+	ErrCronNoMatch = errors.New("cron sched does not match any future time")
+
+	// cronAliases maps the named schedules from the crontab man page to
+	// their equivalent 5-field expression.
+	cronAliases = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// CronSched is the extended representation of a job sched: unlike Sched,
+// where every field holds a single int, each field here holds the sorted
+// set of values parsed out of a cron expression (e.g. "1,15,30" or "*/5").
+type CronSched struct {
+	Second  []int
+	Minute  []int
+	Hour    []int
+	Day     []int
+	Month   []int
+	Weekday []int
+}
+
+// ParseCron parses a standard 5-field ("minute hour day month weekday") or
+// 6-field (with a leading "second") cron expression, or one of the named
+// aliases such as "@hourly", into a CronSched.
+func ParseCron(expr string) (*CronSched, error) {
+	expr = strings.TrimSpace(expr)
+	if alias, ok := cronAliases[expr]; ok {
+		expr = alias
+	}
+
+	fields := strings.Fields(expr)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+		// already has a seconds field
+	default:
+		return nil, fmt.Errorf("%s: got %d", ErrCronFields, len(fields))
+	}
+
+	second, err := parseCronField(fields[0], 0, 59)
+	if err != nil {
+		return nil, err
+	}
+	minute, err := parseCronField(fields[1], 0, 59)
+	if err != nil {
+		return nil, err
+	}
+	hour, err := parseCronField(fields[2], 0, 23)
+	if err != nil {
+		return nil, err
+	}
+	day, err := parseCronField(fields[3], 1, 31)
+	if err != nil {
+		return nil, err
+	}
+	month, err := parseCronField(fields[4], 1, 12)
+	if err != nil {
+		return nil, err
+	}
+	weekday, err := parseCronField(fields[5], 0, 6)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CronSched{
+		Second:  second,
+		Minute:  minute,
+		Hour:    hour,
+		Day:     day,
+		Month:   month,
+		Weekday: weekday,
+	}, nil
+}
+
+// parseCronField parses a single cron field, a comma separated list of
+// "*", "*/n", "a", "a-b" or "a-b/n" items, into the sorted set of values it
+// represents.
+func parseCronField(field string, min, max int) ([]int, error) {
+	set := map[int]bool{}
+
+	for _, item := range strings.Split(field, ",") {
+		spec := item
+		step := 1
+		if idx := strings.Index(item, "/"); idx != -1 {
+			n, err := strconv.Atoi(item[idx+1:])
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid step %q in field %q", item[idx+1:], field)
+			}
+			step = n
+			spec = item[:idx]
+		}
+
+		var lo, hi int
+		switch {
+		case spec == "*":
+			lo, hi = min, max
+		case strings.Contains(spec, "-"):
+			bounds := strings.SplitN(spec, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return nil, fmt.Errorf("invalid range %q in field %q", spec, field)
+			}
+			if hi, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, fmt.Errorf("invalid range %q in field %q", spec, field)
+			}
+		default:
+			n, err := strconv.Atoi(spec)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q in field %q", spec, field)
+			}
+			lo, hi = n, n
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("value %q out of range [%d, %d] in field %q", spec, min, max, field)
+		}
+
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+
+	values := make([]int, 0, len(set))
+	for v := range set {
+		values = append(values, v)
+	}
+	sort.Ints(values)
+	return values, nil
+}
+
+// Next returns the first time strictly after from that cs matches, computed
+// by field-wise rollover (the same next-occurrence algorithm used by
+// standard cron implementations) rather than a brute-force per-second scan.
+// It gives up with ErrCronNoMatch once the search crosses five years ahead,
+// e.g. "0 0 30 2 *" (February 30th never happens).
+func (cs *CronSched) Next(from time.Time) (time.Time, error) {
+	t := from.Add(time.Second).Truncate(time.Second)
+	yearLimit := t.Year() + 5
+
+WRAP:
+	for {
+		if t.Year() > yearLimit {
+			return time.Time{}, ErrCronNoMatch
+		}
+
+		for !containsInt(cs.Month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()).AddDate(0, 1, 0)
+			if t.Year() > yearLimit {
+				return time.Time{}, ErrCronNoMatch
+			}
+		}
+
+		for !cs.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).AddDate(0, 0, 1)
+			if t.Day() == 1 {
+				continue WRAP // rolled into the next month
+			}
+		}
+
+		for !containsInt(cs.Hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()).Add(time.Hour)
+			if t.Hour() == 0 {
+				continue WRAP // rolled into the next day
+			}
+		}
+
+		for !containsInt(cs.Minute, t.Minute()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location()).Add(time.Minute)
+			if t.Minute() == 0 {
+				continue WRAP // rolled into the next hour
+			}
+		}
+
+		for !containsInt(cs.Second, t.Second()) {
+			t = t.Add(time.Second)
+			if t.Second() == 0 {
+				continue WRAP // rolled into the next minute
+			}
+		}
+
+		return t, nil
+	}
+}
+
+// dayMatches reports whether t satisfies cs's Day and Weekday fields. Per
+// the standard cron rule, when both day-of-month and day-of-week are
+// restricted (not "*"), a match on either is enough; otherwise the
+// unrestricted field trivially matches and the restricted one decides.
+func (cs *CronSched) dayMatches(t time.Time) bool {
+	dayRestricted := len(cs.Day) != 31
+	weekdayRestricted := len(cs.Weekday) != 7
+	if dayRestricted && weekdayRestricted {
+		return containsInt(cs.Day, t.Day()) || containsInt(cs.Weekday, int(t.Weekday()))
+	}
+	return containsInt(cs.Day, t.Day()) && containsInt(cs.Weekday, int(t.Weekday()))
+}
+
+// sched collapses cs down to the single-value-per-field Sched map, for
+// compatibility with code that only understands Second/Minute/.../Month. A
+// field whose set spans its whole range collapses to EveryRune, mirroring
+// what Every() does for "*"; otherwise it collapses to its first value.
+func (cs *CronSched) sched() map[string]int {
+	return map[string]int{
+		Second:  collapseCronField(cs.Second, 0, 59),
+		Minute:  collapseCronField(cs.Minute, 0, 59),
+		Hour:    collapseCronField(cs.Hour, 0, 23),
+		Day:     collapseCronField(cs.Day, 1, 31),
+		Weekday: collapseCronField(cs.Weekday, 0, 6),
+		Month:   collapseCronField(cs.Month, 1, 12),
+	}
+}
+
+func collapseCronField(values []int, min, max int) int {
+	if len(values) == max-min+1 {
+		return EveryRune
+	}
+	return values[0]
+}
+
+// containsInt reports whether the sorted slice values contains n.
+func containsInt(values []int, n int) bool {
+	i := sort.SearchInts(values, n)
+	return i < len(values) && values[i] == n
+}
+
+// schedToCronSched builds the CronSched equivalent of a single-value-per-field
+// Sched map, where EveryRune means "every value of this field", so CronSched.Next
+// can also compute the next fire time for an Every job's Sched.
+func schedToCronSched(sched map[string]int) *CronSched {
+	return &CronSched{
+		Second:  schedFieldValues(sched[Second], 0, 59),
+		Minute:  schedFieldValues(sched[Minute], 0, 59),
+		Hour:    schedFieldValues(sched[Hour], 0, 23),
+		Day:     schedFieldValues(sched[Day], 1, 31),
+		Month:   schedFieldValues(sched[Month], 1, 12),
+		Weekday: schedFieldValues(sched[Weekday], 0, 6),
+	}
+}
+
+// schedFieldValues expands a single Sched field value into the set of values
+// it represents: every value in [min, max] if v is EveryRune, otherwise v
+// alone.
+func schedFieldValues(v, min, max int) []int {
+	if v != EveryRune {
+		return []int{v}
+	}
+	values := make([]int, 0, max-min+1)
+	for i := min; i <= max; i++ {
+		values = append(values, i)
+	}
+	return values
+}
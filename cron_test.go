@@ -0,0 +1,138 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronFields(t *testing.T) {
+	cs, err := ParseCron("*/15 0-4,8 * * 1-5")
+	if err != nil {
+		t.Fatalf("ParseCron returned error: %v", err)
+	}
+	if len(cs.Second) != 1 || cs.Second[0] != 0 {
+		t.Errorf("5-field expression should default Second to [0], got %v", cs.Second)
+	}
+	wantMinute := []int{0, 15, 30, 45}
+	if len(cs.Minute) != len(wantMinute) {
+		t.Errorf("Minute = %v, want %v", cs.Minute, wantMinute)
+	}
+	for i, v := range wantMinute {
+		if cs.Minute[i] != v {
+			t.Errorf("Minute = %v, want %v", cs.Minute, wantMinute)
+			break
+		}
+	}
+	wantHour := []int{0, 1, 2, 3, 4, 8}
+	for i, v := range wantHour {
+		if cs.Hour[i] != v {
+			t.Errorf("Hour = %v, want %v", cs.Hour, wantHour)
+			break
+		}
+	}
+	wantWeekday := []int{1, 2, 3, 4, 5}
+	for i, v := range wantWeekday {
+		if cs.Weekday[i] != v {
+			t.Errorf("Weekday = %v, want %v", cs.Weekday, wantWeekday)
+			break
+		}
+	}
+}
+
+func TestParseCronAlias(t *testing.T) {
+	cs, err := ParseCron("@hourly")
+	if err != nil {
+		t.Fatalf("ParseCron returned error: %v", err)
+	}
+	if len(cs.Minute) != 1 || cs.Minute[0] != 0 {
+		t.Errorf("@hourly should fire at minute 0, got %v", cs.Minute)
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	if _, err := ParseCron("* * *"); err == nil {
+		t.Errorf("expression with 3 fields should be rejected")
+	}
+	if _, err := ParseCron("60 * * * *"); err == nil {
+		t.Errorf("out of range minute should be rejected")
+	}
+	if _, err := ParseCron("a * * * *"); err == nil {
+		t.Errorf("non-numeric field should be rejected")
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	cs, err := ParseCron("30 9 * * 1-5")
+	if err != nil {
+		t.Fatalf("ParseCron returned error: %v", err)
+	}
+	from := time.Date(2019, time.September, 2, 9, 30, 0, 0, time.UTC) // a Monday
+	next, err := cs.Next(from)
+	if err != nil {
+		t.Fatalf("Next returned error: %v", err)
+	}
+	want := time.Date(2019, time.September, 3, 9, 30, 0, 0, time.UTC)
+	if !next.Equal(want) {
+		t.Errorf("Next(%v) = %v, want %v", from, next, want)
+	}
+}
+
+// TestCronNextIsFast checks that Next computes sparse and never-matching
+// schedules by field-wise rollover instead of scanning one second at a time,
+// which would otherwise take seconds for a yearly-ish schedule and cross the
+// 5-year search horizon before returning ErrCronNoMatch.
+func TestCronNextIsFast(t *testing.T) {
+	from := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	leap, err := ParseCron("0 0 29 2 *")
+	if err != nil {
+		t.Fatalf("ParseCron returned error: %v", err)
+	}
+	start := time.Now()
+	if _, err := leap.Next(from); err != nil {
+		t.Fatalf("Next returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Next took %v, want a field-wise rollover, not a per-second scan", elapsed)
+	}
+
+	never, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("ParseCron returned error: %v", err)
+	}
+	start = time.Now()
+	if _, err := never.Next(from); err != ErrCronNoMatch {
+		t.Fatalf("Next error = %v, want %v", err, ErrCronNoMatch)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Next took %v, want a field-wise rollover, not a per-second scan", elapsed)
+	}
+}
+
+func TestCron(t *testing.T) {
+	s, _ := NewScheduler(10)
+	j, err := s.Cron("* * * * * *")
+	if err != nil {
+		t.Fatalf("Cron returned error: %v", err)
+	}
+
+	res1 := []string{}
+	res2 := []string{"cat", "5"}
+	jobID := j.Do(task1, "cat", "5", &res1)
+	time.Sleep(2 * time.Second)
+	if err := s.CancelJob(jobID); err != nil {
+		t.Fatalf("CancelJob returned error: %v", err)
+	}
+	if len(res1) < len(res2) {
+		t.Fatalf("Do method error with Cron: got %v", res1)
+	}
+	for i, v := range res2 {
+		if res1[i] != v {
+			t.Errorf("Do method error with Cron")
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// distributedLockTTL is how long a DistributedLock implementation holds a
+// fire's lock before it expires on its own, in case the node that acquired
+// it dies before calling release.
+const distributedLockTTL = 10 * 1000 // milliseconds
+
+// DistributedLock lets several Scheduler processes share the same job
+// config (e.g. N replicas in Kubernetes) while guaranteeing only one of
+// them fires any given occurrence. Acquire is called with a key unique to
+// one job's one occurrence; it reports whether the lock was obtained, and
+// if so a release func to call once the job has finished running.
+type DistributedLock interface {
+	Acquire(ctx context.Context, key string) (acquired bool, release func())
+}
+
+// WithDistributedLock makes the Scheduler coordinate with its DistributedLock
+// before firing any job created with Job.SingletonDistributed, so only one
+// node among several replicas runs a given occurrence.
+func WithDistributedLock(lock DistributedLock) Option {
+	return func(s *Scheduler) {
+		s.distLock = lock
+	}
+}
+
+// SingletonDistributed marks the job as requiring cluster-wide, not just
+// process-wide, dedup: the Scheduler's DistributedLock (set by
+// WithDistributedLock) must be acquired for an occurrence before it is run,
+// and occurrences that lose the race are skipped on this node. It has no
+// effect unless the Scheduler also has a DistributedLock.
+func (j *Job) SingletonDistributed() *Job {
+	j.distributed = true
+	return j
+}
+
+// tryAcquireFire reports whether job's occurrence at fireAt may run on this
+// node. It is a no-op (always true) unless job opted in with
+// SingletonDistributed and the Scheduler has a DistributedLock.
+func (s *Scheduler) tryAcquireFire(job *Job, fireAt time.Time) (release func(), ok bool) {
+	if s.distLock == nil || !job.distributed {
+		return nil, true
+	}
+
+	key := job.ID + "@" + fireAt.UTC().Format(time.RFC3339)
+	acquired, release := s.distLock.Acquire(context.Background(), key)
+	return release, acquired
+}
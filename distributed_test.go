@@ -0,0 +1,87 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeLock is an in-memory DistributedLock, standing in for Redis/etcd in
+// tests: the first Acquire for a key wins, later ones fail until released.
+type fakeLock struct {
+	mu      sync.Mutex
+	claimed map[string]bool
+}
+
+func newFakeLock() *fakeLock {
+	return &fakeLock{claimed: map[string]bool{}}
+}
+
+func (f *fakeLock) Acquire(ctx context.Context, key string) (bool, func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.claimed[key] {
+		return false, nil
+	}
+	f.claimed[key] = true
+	return true, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.claimed, key)
+	}
+}
+
+func TestSingletonDistributed(t *testing.T) {
+	lock := newFakeLock()
+
+	s1, err := NewScheduler(10, WithDistributedLock(lock))
+	if err != nil {
+		t.Fatalf("NewScheduler returned error: %v", err)
+	}
+	s2, err := NewScheduler(10, WithDistributedLock(lock))
+	if err != nil {
+		t.Fatalf("NewScheduler returned error: %v", err)
+	}
+
+	// two replicas running the same job config would use the same job ID;
+	// simulate that here without going through the pendingSet bookkeeping.
+	fireAt := time.Now()
+	job1 := &Job{ID: "shared-occurrence", distributed: true}
+	job2 := &Job{ID: "shared-occurrence", distributed: true}
+
+	_, ok1 := s1.tryAcquireFire(job1, fireAt)
+	_, ok2 := s2.tryAcquireFire(job2, fireAt)
+
+	if ok1 == ok2 {
+		t.Errorf("only one node should acquire the lock for the same occurrence, got ok1=%v ok2=%v", ok1, ok2)
+	}
+}
+
+func TestSingletonDistributedWithoutLock(t *testing.T) {
+	// no WithDistributedLock: SingletonDistributed should have no effect.
+	s, err := NewScheduler(10)
+	if err != nil {
+		t.Fatalf("NewScheduler returned error: %v", err)
+	}
+
+	var mu sync.Mutex
+	ran := false
+	s.Delay().Second(1).SingletonDistributed().Do(func() {
+		mu.Lock()
+		ran = true
+		mu.Unlock()
+	})
+
+	time.Sleep(2 * time.Second)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !ran {
+		t.Errorf("job should still run when no DistributedLock is configured")
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// EtcdLock is a DistributedLock backed by an etcd lease: Acquire creates a
+// short-lived lease and claims key with it via a transaction that only
+// succeeds if the key does not already exist, so the lease's TTL bounds how
+// long the lock survives a node dying before it calls release.
+type EtcdLock struct {
+	client *clientv3.Client
+	ttl    time.Duration
+}
+
+// NewEtcdLock wraps an already-connected *clientv3.Client. ttl bounds the
+// lease used for each occurrence's lock; ttl <= 0 defaults to
+// distributedLockTTL.
+func NewEtcdLock(client *clientv3.Client, ttl time.Duration) *EtcdLock {
+	if ttl <= 0 {
+		ttl = distributedLockTTL * time.Millisecond
+	}
+	return &EtcdLock{client: client, ttl: ttl}
+}
+
+// Acquire implements DistributedLock.
+func (e *EtcdLock) Acquire(ctx context.Context, key string) (bool, func()) {
+	lease, err := e.client.Grant(ctx, int64(e.ttl/time.Second)+1)
+	if err != nil {
+		return false, nil
+	}
+
+	fullKey := "scheduler/lock/" + key
+	txn := e.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(fullKey), "=", 0)).
+		Then(clientv3.OpPut(fullKey, "1", clientv3.WithLease(lease.ID)))
+	resp, err := txn.Commit()
+	if err != nil || !resp.Succeeded {
+		e.client.Revoke(context.Background(), lease.ID)
+		return false, nil
+	}
+
+	return true, func() {
+		e.client.Revoke(context.Background(), lease.ID)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"time"
+)
+
+// defaultWorkerPoolSize is the number of goroutines that run due Every jobs
+// when Scheduler is not given an explicit WithWorkerPoolSize Option.
+const defaultWorkerPoolSize = 100
+
+// everyEntry is one job waiting on the Every timer wheel.
+type everyEntry struct {
+	fireAt time.Time
+	job    *Job
+}
+
+// everyHeap is a min-heap of everyEntry ordered by fireAt, so the wheel
+// goroutine can always sleep until the single earliest deadline instead of
+// waking up every second to scan every job.
+type everyHeap []*everyEntry
+
+func (h everyHeap) Len() int            { return len(h) }
+func (h everyHeap) Less(i, j int) bool  { return h[i].fireAt.Before(h[j].fireAt) }
+func (h everyHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *everyHeap) Push(x interface{}) { *h = append(*h, x.(*everyEntry)) }
+func (h *everyHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	*h = old[:n-1]
+	return entry
+}
+
+// scheduleEvery pushes job onto the Every timer wheel to fire at fireAt and
+// wakes the wheel goroutine, in case fireAt is now the earliest deadline.
+func (s *Scheduler) scheduleEvery(job *Job, fireAt time.Time) {
+	job.setNextRun(fireAt)
+
+	s.everyLock.Lock()
+	heap.Push(&s.everyHeap, &everyEntry{fireAt: fireAt, job: job})
+	s.everyLock.Unlock()
+
+	select {
+	case s.everyWake <- struct{}{}:
+	default:
+	}
+}
+
+// runEveryWheel is the scheduler-owned goroutine that replaces one
+// time.Ticker per Every job with a single min-heap and time.Timer: it sleeps
+// until the earliest deadline across all Every jobs, dispatches whichever
+// jobs are due to the worker pool, and re-arms itself for the new earliest
+// deadline.
+func (s *Scheduler) runEveryWheel() {
+	timer := time.NewTimer(time.Hour)
+	if !timer.Stop() {
+		<-timer.C
+	}
+
+	for {
+		s.everyLock.Lock()
+		d := time.Hour // nothing scheduled yet, just wait to be woken
+		if s.everyHeap.Len() > 0 {
+			if d = time.Until(s.everyHeap[0].fireAt); d < 0 {
+				d = 0
+			}
+		}
+		s.everyLock.Unlock()
+		timer.Reset(d)
+
+		select {
+		case <-timer.C:
+			s.fireDueEvery()
+		case <-s.everyWake:
+			if !timer.Stop() {
+				<-timer.C
+			}
+		case <-s.stopCtx.Done():
+			return
+		}
+	}
+}
+
+// fireDueEvery pops every heap entry whose deadline has passed, dispatches
+// it to the worker pool, and re-schedules it for its next occurrence.
+func (s *Scheduler) fireDueEvery() {
+	now := time.Now()
+
+	s.everyLock.Lock()
+	var due []*everyEntry
+	for s.everyHeap.Len() > 0 && !s.everyHeap[0].fireAt.After(now) {
+		due = append(due, heap.Pop(&s.everyHeap).(*everyEntry))
+	}
+	s.everyLock.Unlock()
+
+	for _, entry := range due {
+		job := entry.job
+		if job.cancelled() {
+			continue
+		}
+
+		fireAt := entry.fireAt
+		select {
+		case s.jobQueue <- func() {
+			job.fireOnce(fireAt)
+			s.js.setJobDone(job.ID)
+		}:
+		case <-s.stopCtx.Done():
+			return
+		}
+
+		if next, err := schedToCronSched(job.Sched).Next(now); err == nil {
+			s.scheduleEvery(job, next)
+		}
+	}
+}
+
+// worker runs dispatched jobs until the scheduler is stopped.
+func (s *Scheduler) worker() {
+	for {
+		select {
+		case task := <-s.jobQueue:
+			task()
+		case <-s.stopCtx.Done():
+			return
+		}
+	}
+}
+
+// cancelled reports whether job has been cancelled and should be dropped
+// the next time it is popped off the Every timer wheel.
+func (j *Job) cancelled() bool {
+	j.cancelMu.Lock()
+	defer j.cancelMu.Unlock()
+	return j.isCancelled
+}
+
+// cancel marks job so the Every timer wheel drops it instead of running it
+// and re-scheduling its next occurrence, and cancels its context.Context if
+// a run() is currently in flight so the job function can unwind promptly.
+func (j *Job) cancel() {
+	j.cancelMu.Lock()
+	defer j.cancelMu.Unlock()
+	j.isCancelled = true
+	if j.fireCancel != nil {
+		j.fireCancel()
+	}
+}
+
+// setFireCancel records the cancel func for the context.Context passed to
+// the run() currently in flight, so cancel() can stop it early.
+func (j *Job) setFireCancel(cancel context.CancelFunc) {
+	j.cancelMu.Lock()
+	defer j.cancelMu.Unlock()
+	j.fireCancel = cancel
+}
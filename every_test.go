@@ -0,0 +1,35 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEveryManyJobs(t *testing.T) {
+	s, _ := NewScheduler(100)
+
+	n := 50
+	counts := make([]int, n)
+	ids := make([]string, n)
+	for i := 0; i < n; i++ {
+		i := i
+		ids[i] = s.Every().Do(func() { counts[i]++ })
+	}
+
+	time.Sleep(2500 * time.Millisecond)
+	for i := range ids {
+		if err := s.CancelJob(ids[i]); err != nil {
+			t.Fatalf("CancelJob returned error: %v", err)
+		}
+	}
+
+	for i, c := range counts {
+		if c == 0 {
+			t.Errorf("job %d never ran", i)
+		}
+	}
+}
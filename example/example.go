@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/prprprus/scheduler"
@@ -15,8 +17,34 @@ func task2() {
 	fmt.Println("run task2, without arguments")
 }
 
+// inProcessLock is a trivial DistributedLock, standing in here for the
+// RedisLock/EtcdLock implementations a real multi-node deployment would use:
+// the first Acquire for a key wins until its release is called.
+type inProcessLock struct {
+	mu      sync.Mutex
+	claimed map[string]bool
+}
+
+func newInProcessLock() *inProcessLock {
+	return &inProcessLock{claimed: map[string]bool{}}
+}
+
+func (l *inProcessLock) Acquire(ctx context.Context, key string) (bool, func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.claimed[key] {
+		return false, nil
+	}
+	l.claimed[key] = true
+	return true, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.claimed, key)
+	}
+}
+
 func main() {
-	s, err := scheduler.NewScheduler(1000)
+	s, err := scheduler.NewScheduler(1000, scheduler.WithWorkerPoolSize(50))
 	if err != nil {
 		panic(err) // just example
 	}
@@ -67,6 +95,117 @@ func main() {
 		fmt.Println("cancel periodically job success")
 	}
 
+	// job options: run at most 3 times, skip overlapping fires, tag for lookup
+	optJob := s.Every().Second(1)
+	optJob.LimitRunsTo(3).SingletonMode().Tag("demo", "every-second")
+	optJob.Do(task2)
+
+	for _, tagged := range s.JobsByTag("demo") {
+		fmt.Println("tagged job:", tagged.ID)
+	}
+
+	// retry with exponential backoff and failure/success callbacks
+	retryJob := s.Delay().Second(1)
+	retryJob.WithRetry(3, scheduler.NewExponentialBackoff(time.Second, 10*time.Second, true))
+	retryJob.OnError(func(jobID string, err error, attempt int) {
+		fmt.Printf("job %s attempt %d failed: %v\n", jobID, attempt, err)
+	})
+	retryJob.OnSuccess(func(jobID string) {
+		fmt.Printf("job %s succeeded\n", jobID)
+	})
+	retryJob.Do(task2)
+
+	fmt.Println()
+	fmt.Println("--------------------------------------------------")
+	fmt.Println()
+
+	// Cron expression, run every second
+	j, err := s.Cron("* * * * * *")
+	if err != nil {
+		panic(err) // just example
+	}
+	jobID = j.Do(task2)
+
+	// named alias
+	_, err = s.Cron("@hourly")
+	if err != nil {
+		panic(err) // just example
+	}
+
+	time.Sleep(3 * time.Second)
+	err = s.CancelJob(jobID)
+	if err != nil {
+		panic(err)
+	} else {
+		fmt.Println("cancel cron job success")
+	}
+
+	fmt.Println()
+	fmt.Println("--------------------------------------------------")
+	fmt.Println()
+
+	// persistent jobs: DoNamed survives a process restart, as long as the
+	// function is re-registered with RegisterFunc and the Scheduler is
+	// created with the same Store
+	scheduler.RegisterFunc("task2", task2)
+	store := scheduler.NewMemoryStore()
+	sp, err := scheduler.NewScheduler(10, scheduler.WithStore(store))
+	if err != nil {
+		panic(err) // just example
+	}
+	sp.Every().DoNamed("task2")
+
+	time.Sleep(3 * time.Second)
+	fmt.Println()
+	fmt.Println("--------------------------------------------------")
+	fmt.Println()
+
+	// distributed scheduling: wire a DistributedLock (RedisLock/EtcdLock in
+	// production; a trivial in-process one here) so that when several
+	// replicas share the same job config, only one of them fires a given
+	// occurrence of a job marked SingletonDistributed
+	lock := newInProcessLock()
+	distNode, err := scheduler.NewScheduler(10, scheduler.WithDistributedLock(lock))
+	if err != nil {
+		panic(err) // just example
+	}
+	distNode.Delay().Second(1).SingletonDistributed().Do(func() {
+		fmt.Println("fired the occurrence after acquiring the distributed lock")
+	})
+
+	time.Sleep(2 * time.Second)
+	fmt.Println()
+	fmt.Println("--------------------------------------------------")
+	fmt.Println()
+
+	// graceful shutdown: a job function may accept a context.Context as its
+	// first parameter, which is cancelled when Stop is called, so a
+	// long-running task can unwind promptly instead of leaking past the
+	// Scheduler's lifetime
+	sg, err := scheduler.NewScheduler(10)
+	if err != nil {
+		panic(err) // just example
+	}
+	sg.StartAsync()
+	sg.Every().Second(1).Do(func(ctx context.Context) {
+		select {
+		case <-time.After(500 * time.Millisecond):
+			fmt.Println("context-aware task finished normally")
+		case <-ctx.Done():
+			fmt.Println("context-aware task cancelled:", ctx.Err())
+		}
+	})
+
+	time.Sleep(1500 * time.Millisecond)
+	stopCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := sg.Stop(stopCtx); err != nil {
+		fmt.Println("scheduler did not stop cleanly:", err)
+	} else {
+		fmt.Println("scheduler stopped gracefully")
+	}
+	sg.StartBlocking() // returns immediately: stopCtx is already done
+
 	fmt.Println()
 	fmt.Println("--------------------------------------------------")
 }
@@ -0,0 +1,132 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+package scheduler
+
+import "time"
+
+// SingletonMode makes overlapping fires of the job skip instead of stack: if
+// the previous invocation of the job function is still running when the
+// next one comes due, the new one is dropped rather than queued behind it.
+func (j *Job) SingletonMode() *Job {
+	j.singleton = true
+	return j
+}
+
+// LimitRunsTo auto-cancels the job once it has run n times. It is mainly
+// useful for Every and Cron jobs, which otherwise run forever.
+func (j *Job) LimitRunsTo(n int) *Job {
+	j.limitRuns = n
+	return j
+}
+
+// Tag attaches tags to the job, for later lookup with
+// Scheduler.JobsByTag and Scheduler.CancelJobsByTag.
+func (j *Job) Tag(tags ...string) *Job {
+	j.tags = append(j.tags, tags...)
+	return j
+}
+
+// StartAt delays the job's first fire to no earlier than t. It has no
+// effect on a Delay job, whose single fire time is already set by its Sched.
+func (j *Job) StartAt(t time.Time) *Job {
+	j.startAt = t
+	return j
+}
+
+// StartImmediately makes the job fire once as soon as Do is called, in
+// addition to firing on its normal Sched/CronSched afterwards.
+func (j *Job) StartImmediately() *Job {
+	j.startImmediately = true
+	return j
+}
+
+// LastRun returns the time of the job's last execution, and whether it has
+// run at least once.
+func (j *Job) LastRun() (time.Time, bool) {
+	j.statsMu.RLock()
+	defer j.statsMu.RUnlock()
+	return j.lastRun, j.runCount > 0
+}
+
+// NextRun returns the time the job is next scheduled to fire, and whether
+// one is known yet.
+func (j *Job) NextRun() (time.Time, bool) {
+	j.statsMu.RLock()
+	defer j.statsMu.RUnlock()
+	return j.nextRun, !j.nextRun.IsZero()
+}
+
+// RunCount returns the number of times the job's function has been invoked.
+func (j *Job) RunCount() int {
+	j.statsMu.RLock()
+	defer j.statsMu.RUnlock()
+	return j.runCount
+}
+
+// setNextRun records when the job is next due, for NextRun to report.
+func (j *Job) setNextRun(t time.Time) {
+	j.statsMu.Lock()
+	j.nextRun = t
+	j.statsMu.Unlock()
+}
+
+// recordRun records that the job's function just ran, and cancels the job
+// once LimitRunsTo's cap has been reached.
+func (j *Job) recordRun() {
+	j.statsMu.Lock()
+	j.lastRun = time.Now()
+	j.runCount++
+	reachedLimit := j.limitRuns > 0 && j.runCount >= j.limitRuns
+	lastRun := j.lastRun
+	j.statsMu.Unlock()
+
+	if j.scheduler != nil && j.scheduler.store != nil && j.funcName != "" {
+		_ = j.scheduler.store.UpdateLastRun(j.ID, lastRun)
+	}
+
+	if reachedLimit {
+		j.cancel()
+	}
+}
+
+// firstFireFrom applies StartAt to from, returning the time Next searches
+// forward from for a job's first fire. StartAt has no effect once it is in
+// the past.
+func (j *Job) firstFireFrom(from time.Time) time.Time {
+	if !j.startAt.IsZero() && j.startAt.After(from) {
+		// Next searches strictly after the time given, so back up one
+		// second to allow startAt itself to match.
+		return j.startAt.Add(-time.Second)
+	}
+	return from
+}
+
+// JobsByTag returns every pending job tagged with tag.
+func (s *Scheduler) JobsByTag(tag string) []*Job {
+	s.js.lock.Lock()
+	defer s.js.lock.Unlock()
+
+	var jobs []*Job
+	for _, job := range s.js.pendingSet {
+		for _, t := range job.tags {
+			if t == tag {
+				jobs = append(jobs, job)
+				break
+			}
+		}
+	}
+	return jobs
+}
+
+// CancelJobsByTag cancels every pending job tagged with tag. It keeps going
+// if cancelling one of them fails, and returns the last error encountered.
+func (s *Scheduler) CancelJobsByTag(tag string) error {
+	var err error
+	for _, job := range s.JobsByTag(tag) {
+		if e := s.CancelJob(job.ID); e != nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+package scheduler
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// ctxType is the reflect.Type of context.Context, used by buildCallArgs to
+// detect a job function with signature func(ctx context.Context, args...).
+var ctxType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// wantsContext reports whether fn's first parameter is a context.Context.
+func wantsContext(fn reflect.Value) bool {
+	t := fn.Type()
+	return t.NumIn() > 0 && t.In(0) == ctxType
+}
+
+// buildCallArgs builds the reflect.Value arguments to invoke fn with,
+// prepending ctx only if fn wants one (see wantsContext).
+func buildCallArgs(fn reflect.Value, ctx context.Context, args []interface{}) []reflect.Value {
+	offset := 0
+	if wantsContext(fn) {
+		offset = 1
+	}
+
+	rArgs := make([]reflect.Value, len(args)+offset)
+	if offset == 1 {
+		rArgs[0] = reflect.ValueOf(ctx)
+	}
+	for i, v := range args {
+		rArgs[i+offset] = reflect.ValueOf(v)
+	}
+	return rArgs
+}
+
+// fireOnce runs job for the occurrence at fireAt, unless the scheduler has
+// since been stopped or another node already claimed the occurrence via a
+// DistributedLock. It is the single place that builds the per-fire
+// context.Context passed to context-aware job functions, and registers it
+// with the job so CancelJob/LimitRunsTo can cancel a run already in flight.
+func (j *Job) fireOnce(fireAt time.Time) {
+	s := j.scheduler
+
+	// Registered before the stopCtx check below, not after, so there is no
+	// window where Stop's wg.Wait() can observe a zero counter and return
+	// while this fire is still about to run.
+	s.wg.Add(1)
+	defer s.wg.Done()
+
+	select {
+	case <-s.stopCtx.Done():
+		return
+	default:
+	}
+
+	release, ok := s.tryAcquireFire(j, fireAt)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(s.stopCtx)
+	j.setFireCancel(cancel)
+	defer func() {
+		cancel()
+		j.setFireCancel(nil)
+	}()
+
+	j.run(ctx)
+
+	if release != nil {
+		release()
+	}
+}
+
+// Stop cancels every pending job (stopping their timers and, for a run
+// already in flight, its context.Context), then waits for those in-flight
+// run() calls to return or for ctx to expire, whichever comes first. A
+// Scheduler must not be used again once Stop has been called.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.stopOnce.Do(func() {
+		s.stopCancel()
+
+		s.js.lock.Lock()
+		for _, job := range s.js.pendingSet {
+			job.cancel()
+			if job.JTimer != nil {
+				job.JTimer.timer.Stop()
+			}
+		}
+		s.js.lock.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// StartAsync exists for lifecycle symmetry with Stop: NewScheduler already
+// starts the worker pool and timer wheel eagerly, so there is nothing left
+// to start. Code that manages a Scheduler's lifecycle explicitly can still
+// call it to make that lifecycle clear to a reader.
+func (s *Scheduler) StartAsync() {
+}
+
+// StartBlocking blocks the calling goroutine until Stop is called. It is
+// meant to be the last line of a program that manages the Scheduler's
+// lifecycle explicitly with StartAsync/StartBlocking/Stop.
+func (s *Scheduler) StartBlocking() {
+	<-s.stopCtx.Done()
+}
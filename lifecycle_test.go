@@ -0,0 +1,163 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestContextAwareJobFunc(t *testing.T) {
+	s, err := NewScheduler(10)
+	if err != nil {
+		t.Fatalf("NewScheduler returned error: %v", err)
+	}
+
+	var mu sync.Mutex
+	var sawCtx bool
+	var errDuringRun error
+	var gotArg string
+
+	s.Delay().Second(1).Do(func(ctx context.Context, arg string) {
+		mu.Lock()
+		sawCtx = ctx != nil
+		errDuringRun = ctx.Err()
+		gotArg = arg
+		mu.Unlock()
+	}, "prprprus")
+
+	time.Sleep(2 * time.Second)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !sawCtx {
+		t.Fatal("context-aware job function should have received a non-nil context.Context")
+	}
+	if gotArg != "prprprus" {
+		t.Errorf("gotArg = %q, want %q", gotArg, "prprprus")
+	}
+	if errDuringRun != nil {
+		t.Errorf("context should not be cancelled while the job is still running, got err: %v", errDuringRun)
+	}
+}
+
+func TestStopWaitsForInFlightRun(t *testing.T) {
+	s, err := NewScheduler(10)
+	if err != nil {
+		t.Fatalf("NewScheduler returned error: %v", err)
+	}
+
+	started := make(chan struct{})
+	finished := make(chan struct{})
+	s.Delay().Second(1).Do(func() {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		close(finished)
+	})
+
+	<-started
+	stopCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := s.Stop(stopCtx); err != nil {
+		t.Fatalf("Stop returned error: %v", err)
+	}
+
+	select {
+	case <-finished:
+	default:
+		t.Error("Stop should wait for the in-flight run() to finish")
+	}
+}
+
+func TestStopCancelsJobContext(t *testing.T) {
+	s, err := NewScheduler(10)
+	if err != nil {
+		t.Fatalf("NewScheduler returned error: %v", err)
+	}
+
+	started := make(chan struct{})
+	unblocked := make(chan error, 1)
+	s.Delay().Second(1).Do(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		unblocked <- ctx.Err()
+	})
+
+	<-started
+	go s.Stop(context.Background())
+
+	select {
+	case err := <-unblocked:
+		if err != context.Canceled {
+			t.Errorf("ctx.Err() = %v, want context.Canceled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("Stop should cancel the in-flight job's context, unblocking it promptly")
+	}
+}
+
+func TestStopPreventsFutureFires(t *testing.T) {
+	s, err := NewScheduler(10)
+	if err != nil {
+		t.Fatalf("NewScheduler returned error: %v", err)
+	}
+
+	var mu sync.Mutex
+	ran := false
+	s.Every().Do(func() {
+		mu.Lock()
+		ran = true
+		mu.Unlock()
+	})
+
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop returned error: %v", err)
+	}
+
+	mu.Lock()
+	ran = false
+	mu.Unlock()
+
+	time.Sleep(2 * time.Second)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if ran {
+		t.Error("an Every job should not fire again after Stop")
+	}
+}
+
+func TestStartBlockingReturnsAfterStop(t *testing.T) {
+	s, err := NewScheduler(10)
+	if err != nil {
+		t.Fatalf("NewScheduler returned error: %v", err)
+	}
+	s.StartAsync()
+
+	done := make(chan struct{})
+	go func() {
+		s.StartBlocking()
+		close(done)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	select {
+	case <-done:
+		t.Fatal("StartBlocking should not return before Stop is called")
+	default:
+	}
+
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop returned error: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Error("StartBlocking should return once Stop is called")
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisLock is a DistributedLock backed by Redis's SET NX PX: Acquire
+// succeeds only for the first node to SET the key, and the key expires on
+// its own after ttl in case that node dies before releasing it.
+type RedisLock struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// redisReleaseScript deletes the lock key only if it still holds the token
+// this Acquire call set, so a release can never delete a different node's
+// lock that has since claimed the same key because this node's ttl expired
+// before it called release.
+var redisReleaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// NewRedisLock wraps an already-connected *redis.Client. ttl bounds how long
+// a lock is held if its owner dies before calling release; it should be
+// comfortably longer than a single job run but short enough that a missed
+// release does not block the next occurrence for long. ttl <= 0 defaults to
+// distributedLockTTL.
+func NewRedisLock(client *redis.Client, ttl time.Duration) *RedisLock {
+	if ttl <= 0 {
+		ttl = distributedLockTTL * time.Millisecond
+	}
+	return &RedisLock{client: client, ttl: ttl}
+}
+
+// Acquire implements DistributedLock.
+func (r *RedisLock) Acquire(ctx context.Context, key string) (bool, func()) {
+	fullKey := "scheduler:lock:" + key
+	token := generateID()
+	ok, err := r.client.SetNX(ctx, fullKey, token, r.ttl).Result()
+	if err != nil || !ok {
+		return false, nil
+	}
+
+	return true, func() {
+		redisReleaseScript.Run(context.Background(), r.client, []string{fullKey}, token)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+package scheduler
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"time"
+)
+
+const (
+	// defaultRetryMaxAttempts is used when a job does not configure
+	// WithRetry: one initial attempt plus one retry, matching the
+	// hardcoded behavior this package shipped with before WithRetry existed.
+	defaultRetryMaxAttempts = 2
+)
+
+// defaultRetryBackoff is used when a job does not configure WithRetry.
+var defaultRetryBackoff BackoffStrategy = ConstantBackoff{Delay: 5 * time.Second}
+
+// BackoffStrategy computes the delay to wait before retrying a failed job,
+// given the number of the attempt that just failed (the first attempt is 1).
+type BackoffStrategy interface {
+	NextDelay(attempt int) time.Duration
+}
+
+// ConstantBackoff waits the same delay before every retry.
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+// NextDelay implements BackoffStrategy.
+func (b ConstantBackoff) NextDelay(attempt int) time.Duration {
+	return b.Delay
+}
+
+// LinearBackoff increases the delay by Step on every retry, starting from
+// Base, capped at Max (no cap if Max is 0).
+type LinearBackoff struct {
+	Base time.Duration
+	Step time.Duration
+	Max  time.Duration
+}
+
+// NextDelay implements BackoffStrategy.
+func (b LinearBackoff) NextDelay(attempt int) time.Duration {
+	d := b.Base + b.Step*time.Duration(attempt-1)
+	if b.Max > 0 && d > b.Max {
+		return b.Max
+	}
+	return d
+}
+
+// ExponentialBackoff doubles the delay on every retry starting from Base,
+// capped at Max (no cap if Max is 0). With Jitter set, the delay is randomized
+// between half and the full computed value, to avoid a thundering herd of
+// retries all firing at the same instant.
+type ExponentialBackoff struct {
+	Base   time.Duration
+	Max    time.Duration
+	Jitter bool
+}
+
+// NewExponentialBackoff builds an ExponentialBackoff from base, max and
+// jitter.
+func NewExponentialBackoff(base, max time.Duration, jitter bool) *ExponentialBackoff {
+	return &ExponentialBackoff{Base: base, Max: max, Jitter: jitter}
+}
+
+// NextDelay implements BackoffStrategy.
+func (b *ExponentialBackoff) NextDelay(attempt int) time.Duration {
+	d := b.Base * time.Duration(int64(1)<<uint(attempt-1))
+	if b.Max > 0 && d > b.Max {
+		d = b.Max
+	}
+	if b.Jitter {
+		half := d / 2
+		d = half + time.Duration(rand.Int63n(int64(half)+1))
+	}
+	return d
+}
+
+// WithRetry configures the job to retry up to maxAttempts times (including
+// the first attempt) when its function panics, waiting
+// backoff.NextDelay(attempt) between attempts. Without WithRetry a job
+// retries once after a constant 5 second delay.
+func (j *Job) WithRetry(maxAttempts int, backoff BackoffStrategy) *Job {
+	j.retryMaxAttempts = maxAttempts
+	j.retryBackoff = backoff
+	return j
+}
+
+// OnError registers a callback invoked every time an attempt at running the
+// job's function panics.
+func (j *Job) OnError(fn func(jobID string, err error, attempt int)) *Job {
+	j.onError = fn
+	return j
+}
+
+// OnSuccess registers a callback invoked after the job's function returns
+// without panicking.
+func (j *Job) OnSuccess(fn func(jobID string)) *Job {
+	j.onSuccess = fn
+	return j
+}
+
+// callJobFunc invokes fn with args and converts a panic into an error
+// instead of letting it propagate, so a failing attempt can be retried
+// without risking a second panic crashing the process.
+func callJobFunc(fn reflect.Value, args []reflect.Value) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = e
+			} else {
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+
+	fn.Call(args)
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConstantBackoff(t *testing.T) {
+	b := ConstantBackoff{Delay: 2 * time.Second}
+	if b.NextDelay(1) != 2*time.Second || b.NextDelay(5) != 2*time.Second {
+		t.Errorf("ConstantBackoff should return the same delay for every attempt")
+	}
+}
+
+func TestLinearBackoff(t *testing.T) {
+	b := LinearBackoff{Base: time.Second, Step: time.Second, Max: 3 * time.Second}
+	if b.NextDelay(1) != time.Second {
+		t.Errorf("NextDelay(1) = %v, want 1s", b.NextDelay(1))
+	}
+	if b.NextDelay(2) != 2*time.Second {
+		t.Errorf("NextDelay(2) = %v, want 2s", b.NextDelay(2))
+	}
+	if b.NextDelay(10) != 3*time.Second {
+		t.Errorf("NextDelay(10) = %v, want the 3s cap", b.NextDelay(10))
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := NewExponentialBackoff(time.Second, 10*time.Second, false)
+	if b.NextDelay(1) != time.Second {
+		t.Errorf("NextDelay(1) = %v, want 1s", b.NextDelay(1))
+	}
+	if b.NextDelay(2) != 2*time.Second {
+		t.Errorf("NextDelay(2) = %v, want 2s", b.NextDelay(2))
+	}
+	if b.NextDelay(3) != 4*time.Second {
+		t.Errorf("NextDelay(3) = %v, want 4s", b.NextDelay(3))
+	}
+	if b.NextDelay(10) != 10*time.Second {
+		t.Errorf("NextDelay(10) = %v, want the 10s cap", b.NextDelay(10))
+	}
+}
+
+func TestWithRetryAndCallbacks(t *testing.T) {
+	s, _ := NewScheduler(10)
+
+	var attempts []int
+	var gotErr error
+	succeeded := false
+
+	j := s.Delay()
+	j.WithRetry(3, ConstantBackoff{Delay: 100 * time.Millisecond})
+	j.OnError(func(jobID string, err error, attempt int) {
+		attempts = append(attempts, attempt)
+		gotErr = err
+	})
+	j.OnSuccess(func(jobID string) {
+		succeeded = true
+	})
+
+	calls := 0
+	j.Do(func() {
+		calls++
+		if calls < 3 {
+			panic(errors.New("transient failure"))
+		}
+	})
+
+	time.Sleep(1 * time.Second)
+
+	if len(attempts) != 2 || attempts[0] != 1 || attempts[1] != 2 {
+		t.Errorf("OnError should fire once per failed attempt, got %v", attempts)
+	}
+	if gotErr == nil || gotErr.Error() != "transient failure" {
+		t.Errorf("OnError should receive the panic value as an error, got %v", gotErr)
+	}
+	if !succeeded {
+		t.Errorf("OnSuccess should fire once the job function stops panicking")
+	}
+}
@@ -7,6 +7,7 @@
 package scheduler
 
 import (
+	"context"
 	"crypto/md5"
 	"errors"
 	"fmt"
@@ -23,6 +24,10 @@ const (
 	// Every represents job type, the job will be cycled execute according to job sched
 	Every = "Every"
 
+	// Cron represents job type, the job will be cycled execute according to a
+	// cron expression parsed into job.CronSched
+	Cron = "Cron"
+
 	// Key of job sched
 	Second  = "Second"
 	Minute  = "Minute"
@@ -93,13 +98,6 @@ var (
 
 	// EmptySched represents an empty job sched
 	EmptySched = map[string]int{}
-
-	// jobSet is a instance of JobSet
-	jobSet = &JobSet{
-		lock:         new(sync.Mutex),
-		pendingSet:   map[string]*Job{},
-		completedSet: map[string]bool{},
-	}
 )
 
 // JobSet
@@ -111,6 +109,17 @@ type JobSet struct {
 	completedSet map[string]bool // storage completed jobs
 }
 
+// newJobSet returns an empty JobSet. Each Scheduler owns one; they used to
+// share a single package-level instance, which meant jobs created from one
+// Scheduler were visible to every other Scheduler in the process.
+func newJobSet() *JobSet {
+	return &JobSet{
+		lock:         new(sync.Mutex),
+		pendingSet:   map[string]*Job{},
+		completedSet: map[string]bool{},
+	}
+}
+
 // setJobDone When the job function is executed then set job done.
 func (js *JobSet) setJobDone(id string) {
 	js.lock.Lock()
@@ -118,8 +127,8 @@ func (js *JobSet) setJobDone(id string) {
 
 	job := js.pendingSet[id]
 
-	// note: ignore with job type is Every
-	if job.Type != Every {
+	// note: ignore with job type is Every or Cron, they run forever
+	if job.Type != Every && job.Type != Cron {
 		if _, ok := js.completedSet[id]; ok {
 			panic(ErrDupJobID)
 		}
@@ -137,12 +146,6 @@ type JobTimer struct {
 	timer *time.Timer // wrapper time.Timer
 }
 
-// JobTicker is the wrapper for time.Ticker, one job corresponds to a JobTicker.
-type JobTicker struct {
-	ID     string       // unique id
-	ticker *time.Ticker // wrapper time.Ticker
-}
-
 // Job is an abstraction of a scheduling task.
 type Job struct {
 	ID   string // unique id
@@ -152,10 +155,58 @@ type Job struct {
 	// fixed, can be arranged and combined at will.
 	Sched map[string]int
 
-	fn      interface{}   // job function
-	args    []interface{} // function args
-	JTimer  *JobTimer     // JobTimer
-	JTicker *JobTicker    // JobTicker
+	// CronExpr and CronSched are only set for a Cron job. CronSched is the
+	// extended representation of Sched: it tolerates a set of values per
+	// field instead of a single int, which Sched can not express.
+	CronExpr  string
+	CronSched *CronSched
+
+	fn     interface{}   // job function
+	args   []interface{} // function args
+	JTimer *JobTimer     // JobTimer, used by Delay and Cron jobs
+
+	// scheduler is the Scheduler this job was created from. Every jobs need
+	// it to enqueue themselves onto the scheduler's timer wheel.
+	scheduler *Scheduler
+
+	cancelMu    sync.Mutex
+	isCancelled bool
+
+	// fireCancel, when set, cancels the context.Context passed to the
+	// currently in-flight run() invocation, so cancel() can unwind a
+	// running job function promptly instead of only stopping future fires.
+	fireCancel context.CancelFunc
+
+	tags      []string // set by Tag, looked up by Scheduler.JobsByTag
+	limitRuns int      // set by LimitRunsTo, 0 means unlimited
+	singleton bool     // set by SingletonMode
+
+	startAt          time.Time // set by StartAt
+	startImmediately bool      // set by StartImmediately
+
+	singletonMu sync.Mutex // held for the duration of run() when singleton is set
+
+	statsMu  sync.RWMutex // guards lastRun/nextRun/runCount
+	lastRun  time.Time
+	nextRun  time.Time
+	runCount int
+
+	// retryMaxAttempts and retryBackoff are set by WithRetry; zero values
+	// fall back to defaultRetryMaxAttempts/defaultRetryBackoff.
+	retryMaxAttempts int
+	retryBackoff     BackoffStrategy
+
+	onError   func(jobID string, err error, attempt int) // set by OnError
+	onSuccess func(jobID string)                         // set by OnSuccess
+
+	// funcName is set by DoNamed. A non-empty funcName means the job is
+	// persisted to scheduler.store and can be restored across restarts.
+	funcName string
+
+	// distributed is set by SingletonDistributed. It opts the job into
+	// scheduler.distLock coordination, so only one node among several
+	// replicas running the same job config fires a given occurrence.
+	distributed bool
 }
 
 // Second method set Second key for job sched.
@@ -246,33 +297,58 @@ func (j *Job) Do(fn interface{}, args ...interface{}) (jobID string) {
 		j.JTimer.ID = generateID()
 		j.JTimer.timer = time.NewTimer(time.Duration(second) * time.Second)
 		go func() {
-			// wait...
-			<-j.JTimer.timer.C
-			// run job function
-			j.run()
+			// wait, unless the scheduler is stopped first...
+			select {
+			case <-j.JTimer.timer.C:
+				j.fireOnce(time.Now())
+			case <-j.scheduler.stopCtx.Done():
+				return
+			}
 			// set job done
-			jobSet.setJobDone(j.ID)
+			j.scheduler.js.setJobDone(j.ID)
+			if j.scheduler.store != nil && j.funcName != "" {
+				_ = j.scheduler.store.MarkCompleted(j.ID)
+			}
 		}()
 	case Every:
-		// initial job.JTicker (note: also can not put it in a new goroutine)
-		j.JTicker = new(JobTicker)
-		j.JTicker.ID = generateID()
-		j.JTicker.ticker = time.NewTicker(1 * time.Second)
+		now := time.Now()
+		if j.startImmediately {
+			j.scheduler.jobQueue <- func() { j.fireOnce(now) }
+		}
+		// enqueue onto the scheduler-owned min-heap timer wheel instead of
+		// running a per-job time.Ticker that wakes up every second
+		next, err := schedToCronSched(j.Sched).Next(j.firstFireFrom(now))
+		if err != nil {
+			panic(err)
+		}
+		j.scheduler.scheduleEvery(j, next)
+	case Cron:
+		if j.startImmediately {
+			go j.fireOnce(time.Now())
+		}
+		// initial job.JTimer (note: can not put it in a new goroutine)
+		next, err := j.CronSched.Next(j.firstFireFrom(time.Now()))
+		if err != nil {
+			panic(err)
+		}
+		j.setNextRun(next)
+		j.JTimer = new(JobTimer)
+		j.JTimer.ID = generateID()
+		j.JTimer.timer = time.NewTimer(time.Until(next))
 		go func() {
-			// begin ticktock...
-			for t := range j.JTicker.ticker.C {
-				_ = t
-				if (j.Sched[Second] == -1 || j.Sched[Second] == time.Now().Second()) &&
-					(j.Sched[Minute] == -1 || j.Sched[Minute] == time.Now().Minute()) &&
-					(j.Sched[Hour] == -1 || j.Sched[Hour] == time.Now().Hour()) &&
-					(j.Sched[Day] == -1 || j.Sched[Day] == time.Now().Day()) &&
-					(j.Sched[Weekday] == -1 || j.Sched[Weekday] == int(time.Now().Weekday())) &&
-					(j.Sched[Month] == -1 || j.Sched[Month] == int(time.Now().Month())) {
-					// run job function
-					j.run()
-					// set job done
-					jobSet.setJobDone(j.ID)
+			// wait, run, compute the next fire time and re-arm, forever...
+			for range j.JTimer.timer.C {
+				j.fireOnce(time.Now())
+				if j.cancelled() {
+					return
+				}
+				next, err := j.CronSched.Next(time.Now())
+				if err != nil {
+					// sched can no longer match any future time, e.g. Feb 30th
+					return
 				}
+				j.setNextRun(next)
+				j.JTimer.timer.Reset(time.Until(next))
 			}
 		}()
 	default:
@@ -282,23 +358,48 @@ func (j *Job) Do(fn interface{}, args ...interface{}) (jobID string) {
 	return j.ID
 }
 
-// run funtion of job by reflect.
-func (j *Job) run() {
+// run funtion of job by reflect. ctx is cancelled when the scheduler is
+// stopped or this job is cancelled mid-run; a job function with signature
+// func(ctx context.Context, args...) receives it, see buildCallArgs.
+func (j *Job) run(ctx context.Context) {
+	if j.singleton {
+		// drop this fire rather than stack it behind the one still running
+		if !j.singletonMu.TryLock() {
+			return
+		}
+		defer j.singletonMu.Unlock()
+	}
+
+	defer j.recordRun()
+
 	rFn := reflect.ValueOf(j.fn)
-	rArgs := make([]reflect.Value, len(j.args))
-	for i, v := range j.args {
-		rArgs[i] = reflect.ValueOf(v)
+	rArgs := buildCallArgs(rFn, ctx, j.args)
+
+	maxAttempts := j.retryMaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultRetryMaxAttempts
+	}
+	backoff := j.retryBackoff
+	if backoff == nil {
+		backoff = defaultRetryBackoff
 	}
 
-	// retry
-	defer func() {
-		if err := recover(); err != nil {
-			time.Sleep(5 * time.Second) // wait for five seconds for now
-			rFn.Call(rArgs)
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err := callJobFunc(rFn, rArgs)
+		if err == nil {
+			if j.onSuccess != nil {
+				j.onSuccess(j.ID)
+			}
+			return
 		}
-	}()
 
-	rFn.Call(rArgs)
+		if j.onError != nil {
+			j.onError(j.ID, err, attempt)
+		}
+		if attempt < maxAttempts {
+			time.Sleep(backoff.NextDelay(attempt))
+		}
+	}
 }
 
 // Scheduler
@@ -307,10 +408,57 @@ func (j *Job) run() {
 type Scheduler struct {
 	jobSetSize int     // custom size for job set, can not overlength maxJobSetSize
 	js         *JobSet // JobSet
+
+	workerPoolSize int         // number of goroutines that run due Every jobs
+	jobQueue       chan func() // work handed off from the Every timer wheel to the worker pool
+
+	everyHeap everyHeap     // min-heap of pending Every jobs, ordered by next fire time
+	everyLock sync.Mutex    // guards everyHeap
+	everyWake chan struct{} // wakes runEveryWheel when a new earliest deadline is scheduled
+
+	// store persists DoNamed jobs so they survive a process restart.
+	// Defaults to an in-memory store, which does not actually survive one.
+	store Store
+
+	// distLock, if set by WithDistributedLock, coordinates with other
+	// Scheduler processes running the same job config so that only one of
+	// them fires an occurrence of a job marked SingletonDistributed.
+	distLock DistributedLock
+
+	// stopCtx/stopCancel/wg back Stop: stopCtx is the parent of every
+	// fire's context.Context and is cancelled when Stop is called, and wg
+	// tracks in-flight run() calls so Stop can wait for them to finish.
+	stopCtx    context.Context
+	stopCancel context.CancelFunc
+	wg         sync.WaitGroup
+	stopOnce   sync.Once
+}
+
+// Option customizes a Scheduler created by NewScheduler.
+type Option func(*Scheduler)
+
+// WithWorkerPoolSize sets the number of goroutines that run due Every jobs.
+// It defaults to defaultWorkerPoolSize.
+func WithWorkerPoolSize(n int) Option {
+	return func(s *Scheduler) {
+		if n > 0 {
+			s.workerPoolSize = n
+		}
+	}
+}
+
+// WithStore sets the Store used to persist jobs created with Job.DoNamed.
+// It defaults to an in-memory store, which keeps the bookkeeping but does
+// not actually survive a process restart; pass a BoltStore or SQLStore for
+// real durability.
+func WithStore(store Store) Option {
+	return func(s *Scheduler) {
+		s.store = store
+	}
 }
 
 // NewScheduler new Scheduler instance.
-func NewScheduler(jss int) (*Scheduler, error) {
+func NewScheduler(jss int, opts ...Option) (*Scheduler, error) {
 	if jss > maxJobSetSize {
 		return nil, ErrOverlength
 	}
@@ -318,10 +466,30 @@ func NewScheduler(jss int) (*Scheduler, error) {
 		jss = defaultJobSetSize
 	}
 
+	stopCtx, stopCancel := context.WithCancel(context.Background())
 	s := &Scheduler{
-		jobSetSize: jss,
-		js:         jobSet,
+		jobSetSize:     jss,
+		js:             newJobSet(),
+		workerPoolSize: defaultWorkerPoolSize,
+		everyWake:      make(chan struct{}, 1),
+		store:          NewMemoryStore(),
+		stopCtx:        stopCtx,
+		stopCancel:     stopCancel,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.jobQueue = make(chan func(), s.workerPoolSize)
+	for i := 0; i < s.workerPoolSize; i++ {
+		go s.worker()
+	}
+	go s.runEveryWheel()
+
+	if err := s.restoreJobs(); err != nil {
+		return nil, err
+	}
+
 	return s, nil
 }
 
@@ -338,9 +506,10 @@ func (s *Scheduler) Delay() *Job {
 	// create job
 	id := generateID()
 	j := &Job{
-		ID:    id,
-		Type:  Delay,
-		Sched: InitJobSched(Delay),
+		ID:        id,
+		Type:      Delay,
+		Sched:     InitJobSched(Delay),
+		scheduler: s,
 	}
 
 	// put in pending job set
@@ -364,7 +533,8 @@ func (s *Scheduler) Every() *Job {
 		ID:   id,
 		Type: Every,
 		// Sched[...] = -1 <=> cron *
-		Sched: InitJobSched(Every),
+		Sched:     InitJobSched(Every),
+		scheduler: s,
 	}
 
 	// put in pending job set
@@ -372,6 +542,41 @@ func (s *Scheduler) Every() *Job {
 	return j
 }
 
+// Cron method schedule job according to a cron expression, standard 5-field
+// ("minute hour day month weekday") or 6-field (with a leading "second")
+// form, plus named aliases like "@hourly". Unlike Delay/Every it returns an
+// error instead of panicking, since the expression comes from the caller at
+// runtime rather than being built up through chained methods.
+func (s *Scheduler) Cron(expr string) (*Job, error) {
+	cs, err := ParseCron(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	s.js.lock.Lock()
+	defer s.js.lock.Unlock()
+
+	// temporarily handle like this
+	if len(s.js.pendingSet) >= 10000 {
+		panic("pending set is full")
+	}
+
+	// create job
+	id := generateID()
+	j := &Job{
+		ID:        id,
+		Type:      Cron,
+		Sched:     cs.sched(),
+		CronExpr:  expr,
+		CronSched: cs,
+		scheduler: s,
+	}
+
+	// put in pending job set
+	s.js.pendingSet[id] = j
+	return j, nil
+}
+
 // PendingJob get pending job by id.
 func (s *Scheduler) PendingJob(id string) (*Job, error) {
 	s.js.lock.Lock()
@@ -427,6 +632,9 @@ func (s *Scheduler) CancelJob(id string) error {
 
 	// cancel by job type
 	job := s.js.pendingSet[id]
+	if s.store != nil && job.funcName != "" {
+		_ = s.store.DeleteJob(job.ID)
+	}
 	switch job.Type {
 	case Delay:
 		ok := job.JTimer.timer.Stop()
@@ -435,8 +643,15 @@ func (s *Scheduler) CancelJob(id string) error {
 		}
 		return ErrCancelJob
 	case Every:
-		job.JTicker.ticker.Stop()
+		job.cancel()
 		return nil
+	case Cron:
+		job.cancel()
+		ok := job.JTimer.timer.Stop()
+		if ok {
+			return nil
+		}
+		return ErrCancelJob
 	default:
 		return ErrJobType
 	}
@@ -51,6 +51,22 @@ func TestNewScheduler(t *testing.T) {
 	}
 }
 
+func TestWithWorkerPoolSize(t *testing.T) {
+	s, err := NewScheduler(10, WithWorkerPoolSize(3))
+	if err != nil {
+		t.Fatalf("NewScheduler returned error: %v", err)
+	}
+	if s.workerPoolSize != 3 {
+		t.Errorf("workerPoolSize = %d, want 3", s.workerPoolSize)
+	}
+
+	// non-positive sizes are ignored, default is kept
+	s, _ = NewScheduler(10, WithWorkerPoolSize(0))
+	if s.workerPoolSize != defaultWorkerPoolSize {
+		t.Errorf("workerPoolSize = %d, want default %d", s.workerPoolSize, defaultWorkerPoolSize)
+	}
+}
+
 func TestPendingJob(t *testing.T) {
 	s, _ := NewScheduler(10)
 	jobID := s.Delay().Minute(10).Do(task2)
@@ -251,6 +267,98 @@ func TestDo(t *testing.T) {
 	}
 }
 
+func TestLimitRunsTo(t *testing.T) {
+	s, _ := NewScheduler(10)
+	runs := 0
+
+	j := s.Every()
+	j.LimitRunsTo(3)
+	j.Do(func() { runs++ })
+	time.Sleep(3500 * time.Millisecond)
+
+	if runs != 3 {
+		t.Errorf("RunCount limit not respected, got %d runs, want 3", runs)
+	}
+	if n := j.RunCount(); n != 3 {
+		t.Errorf("RunCount() = %d, want 3", n)
+	}
+}
+
+func TestSingletonMode(t *testing.T) {
+	s, _ := NewScheduler(10)
+	running := 0
+	overlapped := false
+
+	j := s.Every()
+	j.SingletonMode()
+	jobID := j.Do(func() {
+		if running > 0 {
+			overlapped = true
+		}
+		running++
+		time.Sleep(1500 * time.Millisecond)
+		running--
+	})
+	time.Sleep(3 * time.Second)
+	if err := s.CancelJob(jobID); err != nil {
+		t.Errorf("CancelJob returned error: %v", err)
+	}
+
+	if overlapped {
+		t.Errorf("SingletonMode should skip overlapping fires")
+	}
+}
+
+func TestTag(t *testing.T) {
+	s, _ := NewScheduler(10)
+	j := s.Every()
+	j.Tag("reports", "daily")
+	jobID := j.Do(task2)
+
+	jobs := s.JobsByTag("reports")
+	if len(jobs) != 1 || jobs[0].ID != jobID {
+		t.Errorf("JobsByTag should find the tagged job")
+	}
+
+	if err := s.CancelJobsByTag("reports"); err != nil {
+		t.Errorf("CancelJobsByTag returned error: %v", err)
+	}
+}
+
+func TestStartAt(t *testing.T) {
+	s, _ := NewScheduler(10)
+	start := time.Now().Add(2 * time.Second)
+
+	j := s.Every()
+	j.StartAt(start)
+	jobID := j.Do(task2)
+	defer s.CancelJob(jobID)
+
+	next, ok := j.NextRun()
+	if !ok {
+		t.Fatalf("NextRun should be known once scheduled")
+	}
+	if next.Before(start.Add(-1 * time.Second)) {
+		t.Errorf("NextRun() = %v, should not fire well before StartAt %v", next, start)
+	}
+}
+
+func TestStartImmediately(t *testing.T) {
+	s, _ := NewScheduler(10)
+	j := s.Every()
+	j.Hour(23).Minute(59).StartImmediately()
+	jobID := j.Do(task2)
+	defer s.CancelJob(jobID)
+
+	time.Sleep(200 * time.Millisecond)
+	if n := j.RunCount(); n < 1 {
+		t.Errorf("StartImmediately should have run the job at least once, RunCount() = %d", n)
+	}
+	if _, ok := j.LastRun(); !ok {
+		t.Errorf("LastRun should report the job has run")
+	}
+}
+
 // util
 
 func TestInitJobSched(t *testing.T) {
@@ -0,0 +1,114 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+package scheduler
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// SQLStoreSchema is the DDL for the table SQLStore expects, written in
+// standard SQL understood by SQLite, Postgres and MySQL alike. Run it once
+// against db before passing db to NewSQLStore.
+const SQLStoreSchema = `
+CREATE TABLE IF NOT EXISTS scheduler_jobs (
+	id        VARCHAR(64) PRIMARY KEY,
+	type      VARCHAR(16) NOT NULL,
+	sched     TEXT NOT NULL,
+	cron_expr TEXT NOT NULL,
+	func_name VARCHAR(255) NOT NULL,
+	args      TEXT NOT NULL,
+	tags      TEXT NOT NULL,
+	last_run  TIMESTAMP NULL
+)`
+
+// SQLStore persists jobs to any database/sql.DB, using a single
+// scheduler_jobs table (see SQLStoreSchema). It is driver-agnostic: the
+// caller opens db with whichever driver they want (sqlite, postgres, mysql,
+// ...) and passes it in already open.
+type SQLStore struct {
+	db *sql.DB
+}
+
+// NewSQLStore wraps an already-open *sql.DB. The scheduler_jobs table must
+// already exist; execute SQLStoreSchema against db beforehand if needed.
+func NewSQLStore(db *sql.DB) *SQLStore {
+	return &SQLStore{db: db}
+}
+
+// SaveJob implements Store.
+func (s *SQLStore) SaveJob(job *PersistedJob) error {
+	sched, err := json.Marshal(job.Sched)
+	if err != nil {
+		return err
+	}
+	tags, err := json.Marshal(job.Tags)
+	if err != nil {
+		return err
+	}
+
+	// portable upsert: delete then insert, rather than a dialect-specific
+	// ON CONFLICT/ON DUPLICATE KEY clause
+	if _, err := s.db.Exec(`DELETE FROM scheduler_jobs WHERE id = ?`, job.ID); err != nil {
+		return err
+	}
+	_, err = s.db.Exec(
+		`INSERT INTO scheduler_jobs (id, type, sched, cron_expr, func_name, args, tags, last_run)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		job.ID, job.Type, string(sched), job.CronExpr, job.FuncName, string(job.Args), string(tags), job.LastRun,
+	)
+	return err
+}
+
+// LoadJobs implements Store.
+func (s *SQLStore) LoadJobs() ([]*PersistedJob, error) {
+	rows, err := s.db.Query(`SELECT id, type, sched, cron_expr, func_name, args, tags, last_run FROM scheduler_jobs`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*PersistedJob
+	for rows.Next() {
+		var (
+			job         PersistedJob
+			sched, tags string
+			args        string
+			lastRun     sql.NullTime
+		)
+		if err := rows.Scan(&job.ID, &job.Type, &sched, &job.CronExpr, &job.FuncName, &args, &tags, &lastRun); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(sched), &job.Sched); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(tags), &job.Tags); err != nil {
+			return nil, err
+		}
+		job.Args = json.RawMessage(args)
+		if lastRun.Valid {
+			job.LastRun = lastRun.Time
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, rows.Err()
+}
+
+// MarkCompleted implements Store.
+func (s *SQLStore) MarkCompleted(id string) error {
+	return s.DeleteJob(id)
+}
+
+// DeleteJob implements Store.
+func (s *SQLStore) DeleteJob(id string) error {
+	_, err := s.db.Exec(`DELETE FROM scheduler_jobs WHERE id = ?`, id)
+	return err
+}
+
+// UpdateLastRun implements Store.
+func (s *SQLStore) UpdateLastRun(id string, t time.Time) error {
+	_, err := s.db.Exec(`UPDATE scheduler_jobs SET last_run = ? WHERE id = ?`, t, id)
+	return err
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrFuncNotRegistered is returned by DoNamed, and silently skipped by
+// restoreJobs, when a job references a function name that was never passed
+// to RegisterFunc.
+var ErrFuncNotRegistered = errors.New("job function not registered")
+
+// PersistedJob is the serializable representation of a Job. A Job's fn is an
+// interface{} holding an arbitrary function value and can not be serialized
+// directly, so a persisted job instead references its function by name
+// (see RegisterFunc) and keeps its arguments JSON-encoded.
+type PersistedJob struct {
+	ID       string
+	Type     string
+	Sched    map[string]int
+	CronExpr string
+	FuncName string
+	Args     json.RawMessage
+	Tags     []string
+	LastRun  time.Time
+}
+
+// Store persists jobs so they can be restored after a process restart.
+// Scheduler calls it only for jobs created with Job.DoNamed, since Do's
+// arbitrary function value has nothing a Store could save.
+type Store interface {
+	SaveJob(job *PersistedJob) error
+	LoadJobs() ([]*PersistedJob, error)
+	MarkCompleted(id string) error
+	DeleteJob(id string) error
+	UpdateLastRun(id string, t time.Time) error
+}
+
+var (
+	funcRegistryMu sync.Mutex
+	funcRegistry   = map[string]interface{}{}
+)
+
+// RegisterFunc registers fn under name, so a Store-backed job can reference
+// it by name via DoNamed and be reconstructed by a later process with
+// restoreJobs, without needing to serialize the function value itself.
+func RegisterFunc(name string, fn interface{}) {
+	funcRegistryMu.Lock()
+	defer funcRegistryMu.Unlock()
+	funcRegistry[name] = fn
+}
+
+func lookupFunc(name string) (interface{}, bool) {
+	funcRegistryMu.Lock()
+	defer funcRegistryMu.Unlock()
+	fn, ok := funcRegistry[name]
+	return fn, ok
+}
+
+// DoNamed behaves like Do, except fn must have already been registered with
+// RegisterFunc under name. Unlike Do, the job is saved to the scheduler's
+// Store (SaveJob), so it can be restored by a later NewScheduler call
+// against the same store.
+func (j *Job) DoNamed(name string, args ...interface{}) (jobID string) {
+	fn, ok := lookupFunc(name)
+	if !ok {
+		panic(ErrFuncNotRegistered)
+	}
+	j.funcName = name
+
+	if j.scheduler != nil && j.scheduler.store != nil {
+		if err := j.scheduler.saveJob(j, args); err != nil {
+			panic(err)
+		}
+	}
+
+	return j.Do(fn, args...)
+}
+
+// saveJob marshals job's args and hands it to s.store.
+func (s *Scheduler) saveJob(job *Job, args []interface{}) error {
+	argsJSON, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+
+	return s.store.SaveJob(&PersistedJob{
+		ID:       job.ID,
+		Type:     job.Type,
+		Sched:    job.Sched,
+		CronExpr: job.CronExpr,
+		FuncName: job.funcName,
+		Args:     argsJSON,
+		Tags:     job.tags,
+	})
+}
+
+// restoreJobs loads every job from s.store and re-arms it, so jobs created
+// with DoNamed before a restart keep firing afterwards. Jobs whose function
+// was not re-registered with RegisterFunc in this process are skipped.
+func (s *Scheduler) restoreJobs() error {
+	persisted, err := s.store.LoadJobs()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range persisted {
+		fn, ok := lookupFunc(p.FuncName)
+		if !ok {
+			continue
+		}
+		var args []interface{}
+		if err := json.Unmarshal(p.Args, &args); err != nil {
+			continue
+		}
+
+		j := &Job{
+			ID:        p.ID,
+			Type:      p.Type,
+			Sched:     p.Sched,
+			CronExpr:  p.CronExpr,
+			tags:      p.Tags,
+			funcName:  p.FuncName,
+			scheduler: s,
+		}
+		if !p.LastRun.IsZero() {
+			// restore the one piece of run history the Store actually
+			// tracks; runCount only needs to be nonzero so LastRun's "has
+			// run at least once" bool is accurate, not an exact count.
+			j.lastRun = p.LastRun
+			j.runCount = 1
+		}
+		if p.Type == Cron {
+			cs, err := ParseCron(p.CronExpr)
+			if err != nil {
+				continue
+			}
+			j.CronSched = cs
+		}
+
+		s.js.lock.Lock()
+		s.js.pendingSet[j.ID] = j
+		s.js.lock.Unlock()
+
+		j.Do(fn, args...)
+	}
+
+	return nil
+}
+
+// MemoryStore is the default Store: it keeps jobs in memory only, which
+// preserves the DoNamed bookkeeping but does not survive a process restart.
+type MemoryStore struct {
+	mu   sync.Mutex
+	jobs map[string]*PersistedJob
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{jobs: map[string]*PersistedJob{}}
+}
+
+// SaveJob implements Store.
+func (m *MemoryStore) SaveJob(job *PersistedJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[job.ID] = job
+	return nil
+}
+
+// LoadJobs implements Store.
+func (m *MemoryStore) LoadJobs() ([]*PersistedJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jobs := make([]*PersistedJob, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// MarkCompleted implements Store.
+func (m *MemoryStore) MarkCompleted(id string) error {
+	return m.DeleteJob(id)
+}
+
+// DeleteJob implements Store.
+func (m *MemoryStore) DeleteJob(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.jobs, id)
+	return nil
+}
+
+// UpdateLastRun implements Store.
+func (m *MemoryStore) UpdateLastRun(id string, t time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if job, ok := m.jobs[id]; ok {
+		job.LastRun = t
+	}
+	return nil
+}
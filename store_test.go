@@ -0,0 +1,133 @@
+// Copyright (c) 2019, prprprus All rights reserved.
+// Use of this source code is governed by a BSD-style .
+// license that can be found in the LICENSE file.
+
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+
+	job := &PersistedJob{ID: "job-1", Type: Delay, FuncName: "noop", Args: []byte("[]")}
+	if err := store.SaveJob(job); err != nil {
+		t.Fatalf("SaveJob returned error: %v", err)
+	}
+
+	jobs, err := store.LoadJobs()
+	if err != nil {
+		t.Fatalf("LoadJobs returned error: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != "job-1" {
+		t.Fatalf("LoadJobs = %v, want the saved job", jobs)
+	}
+
+	now := time.Now()
+	if err := store.UpdateLastRun("job-1", now); err != nil {
+		t.Fatalf("UpdateLastRun returned error: %v", err)
+	}
+	jobs, _ = store.LoadJobs()
+	if !jobs[0].LastRun.Equal(now) {
+		t.Errorf("UpdateLastRun did not update LastRun")
+	}
+
+	if err := store.MarkCompleted("job-1"); err != nil {
+		t.Fatalf("MarkCompleted returned error: %v", err)
+	}
+	jobs, _ = store.LoadJobs()
+	if len(jobs) != 0 {
+		t.Errorf("MarkCompleted should remove the job, got %v", jobs)
+	}
+}
+
+func TestDoNamed(t *testing.T) {
+	store := NewMemoryStore()
+	s, err := NewScheduler(10, WithStore(store))
+	if err != nil {
+		t.Fatalf("NewScheduler returned error: %v", err)
+	}
+
+	res := []string{}
+	RegisterFunc("store-test-task", func(name, age string, out *[]string) {
+		*out = append(*out, name, age)
+	})
+
+	jobID := s.Delay().Second(1).DoNamed("store-test-task", "tiger", "23", &res)
+
+	jobs, err := store.LoadJobs()
+	if err != nil {
+		t.Fatalf("LoadJobs returned error: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != jobID || jobs[0].FuncName != "store-test-task" {
+		t.Fatalf("DoNamed should have saved the job to the store, got %v", jobs)
+	}
+
+	time.Sleep(2 * time.Second)
+	if len(res) != 2 || res[0] != "tiger" || res[1] != "23" {
+		t.Errorf("DoNamed did not run the registered function correctly, got %v", res)
+	}
+
+	jobs, _ = store.LoadJobs()
+	if len(jobs) != 0 {
+		t.Errorf("completing a Delay job should mark it completed in the store, got %v", jobs)
+	}
+}
+
+func TestDoNamedUnregistered(t *testing.T) {
+	defer func() {
+		if err := recover(); err == nil || err != ErrFuncNotRegistered {
+			t.Errorf("DoNamed should panic with ErrFuncNotRegistered for an unknown name")
+		}
+	}()
+
+	s, _ := NewScheduler(10)
+	s.Delay().Second(1).DoNamed("does-not-exist")
+}
+
+func TestRestoreJobs(t *testing.T) {
+	store := NewMemoryStore()
+
+	var mu sync.Mutex
+	count := 0
+	RegisterFunc("restore-test-task", func() {
+		mu.Lock()
+		count++
+		mu.Unlock()
+	})
+
+	s1, err := NewScheduler(10, WithStore(store))
+	if err != nil {
+		t.Fatalf("NewScheduler returned error: %v", err)
+	}
+	s1.Every().DoNamed("restore-test-task")
+
+	jobs, _ := store.LoadJobs()
+	if len(jobs) != 1 {
+		t.Fatalf("expected the Every job to be saved to the store, got %v", jobs)
+	}
+
+	// simulate a process restart: the old process (and its scheduler) is
+	// gone, so stop s1 before a fresh Scheduler against the same store picks
+	// the job back up in restoreJobs and keeps firing it.
+	if err := s1.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop returned error: %v", err)
+	}
+
+	s2, err := NewScheduler(10, WithStore(store))
+	if err != nil {
+		t.Fatalf("NewScheduler returned error: %v", err)
+	}
+	_ = s2
+
+	time.Sleep(2 * time.Second)
+	mu.Lock()
+	defer mu.Unlock()
+	if count == 0 {
+		t.Errorf("restoreJobs should have re-armed the job, got count = %d", count)
+	}
+}